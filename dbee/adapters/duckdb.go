@@ -6,6 +6,8 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"net/url"
+	"strings"
 
 	_ "github.com/marcboeker/go-duckdb"
 
@@ -22,21 +24,50 @@ var _ core.Adapter = (*Duck)(nil)
 
 type Duck struct{}
 
-func (d *Duck) Connect(url string) (core.Driver, error) {
-	db, err := sql.Open("duckdb", url)
+// Connect opens a duckdb database. In addition to a plain file path (or
+// ":memory:"), the url accepts query params that run once at connect time:
+//   - extensions=ext1,ext2,...  installs and loads the given extensions
+//   - attach=alias:dsn,...      attaches external catalogs (e.g. postgres,
+//     mysql, sqlite or a remote parquet/iceberg store) under the given alias
+//
+// e.g. "warehouse.db?extensions=httpfs,postgres&attach=pg:postgres://user@host/db"
+func (d *Duck) Connect(rawURL string) (core.Driver, error) {
+	dsn, rawParams, _ := strings.Cut(rawURL, "?")
+
+	params, err := url.ParseQuery(rawParams)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse duckdb connection params: %w", err)
+	}
+
+	db, err := sql.Open("duckdb", dsn)
 	if err != nil {
 		return nil, fmt.Errorf("unable to connect to duckdb database: %v", err)
 	}
 
-	return &duckDriver{
-		c: builders.NewClient(db),
-	}, nil
+	client := builders.NewClient(db)
+
+	if err := bootstrap(context.Background(), client, params); err != nil {
+		return nil, err
+	}
+
+	driver := &duckDriver{c: client}
+
+	driver.dbName, err = driver.currentDatabase(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return driver, nil
 }
 
-var _ core.Driver = (*duckDriver)(nil)
+var (
+	_ core.Driver           = (*duckDriver)(nil)
+	_ core.DatabaseSwitcher = (*duckDriver)(nil)
+)
 
 type duckDriver struct {
-	c *builders.Client
+	c      *builders.Client
+	dbName string
 }
 
 func (c *duckDriver) Query(ctx context.Context, query string) (core.ResultStream, error) {
@@ -61,31 +92,83 @@ func (c *duckDriver) Query(ctx context.Context, query string) (core.ResultStream
 	return rows, nil
 }
 
+// currentDatabase returns the name of duckdb's currently active catalog.
+func (c *duckDriver) currentDatabase(ctx context.Context) (string, error) {
+	rows, err := c.Query(ctx, "SELECT current_database();")
+	if err != nil {
+		return "", fmt.Errorf("failed to determine current database: %w", err)
+	}
+
+	if !rows.HasNext() {
+		return "", fmt.Errorf("current_database() returned no rows")
+	}
+
+	row, err := rows.Next()
+	if err != nil {
+		return "", err
+	}
+
+	name, ok := row[0].(string)
+	if !ok {
+		return "", fmt.Errorf("unexpected type for current_database(): %T", row[0])
+	}
+
+	return name, nil
+}
+
+func (c *duckDriver) ListDatabases() (current string, available []string, err error) {
+	ctx := context.Background()
+
+	names, err := c.queryStrings(ctx, "SELECT database_name FROM duckdb_databases() WHERE NOT internal ORDER BY database_name;")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to list attached databases: %w", err)
+	}
+
+	return c.dbName, names, nil
+}
+
+func (c *duckDriver) SelectDatabase(name string) error {
+	ctx := context.Background()
+
+	rows, err := c.Query(ctx, fmt.Sprintf("USE %s;", quoteIdent(name)))
+	if err != nil {
+		return fmt.Errorf("failed to switch to database %q: %w", name, err)
+	}
+	for rows.HasNext() {
+		if _, err := rows.Next(); err != nil {
+			return err
+		}
+	}
+
+	c.dbName = name
+
+	return nil
+}
+
 func (c *duckDriver) Structure() ([]*core.Structure, error) {
-	query := `SHOW TABLES;`
+	ctx := context.Background()
 
-	rows, err := c.Query(context.TODO(), query)
+	_, databases, err := c.ListDatabases()
 	if err != nil {
 		return nil, err
 	}
 
-	var schema []*core.Structure
-	for rows.HasNext() {
-		row, err := rows.Next()
+	var structure []*core.Structure
+	for _, dbName := range databases {
+		children, err := c.databaseStructure(ctx, dbName)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("failed to build structure for database %q: %w", dbName, err)
 		}
 
-		// We know for a fact there is only one string field (see query above)
-		table := row[0].(string)
-		schema = append(schema, &core.Structure{
-			Name:   table,
-			Schema: "",
-			Type:   core.StructureTypeTable,
+		structure = append(structure, &core.Structure{
+			Name:     dbName,
+			Schema:   "",
+			Type:     core.StructureTypeDatabase,
+			Children: children,
 		})
 	}
 
-	return schema, nil
+	return structure, nil
 }
 
 func (c *duckDriver) Close() {
@@ -0,0 +1,95 @@
+//go:build cgo && ((darwin && (amd64 || arm64)) || (linux && (amd64 || arm64 || riscv64)))
+
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/kndndrj/nvim-dbee/dbee/core/builders"
+)
+
+// validExtensionName matches the bare identifiers duckdb extensions are
+// named with (e.g. "httpfs", "postgres"), rejecting anything that could
+// break out of the INSTALL/LOAD statement it gets interpolated into.
+var validExtensionName = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// bootstrap installs/loads any requested extensions and attaches any
+// requested external catalogs before the driver is handed back to the
+// caller.
+func bootstrap(ctx context.Context, c *builders.Client, params url.Values) error {
+	for _, ext := range splitCSV(params.Get("extensions")) {
+		if err := installExtension(ctx, c, ext); err != nil {
+			return fmt.Errorf("failed to load extension %q: %w", ext, err)
+		}
+	}
+
+	for _, spec := range splitCSV(params.Get("attach")) {
+		if err := attachDatabase(ctx, c, spec); err != nil {
+			return fmt.Errorf("failed to attach %q: %w", spec, err)
+		}
+	}
+
+	return nil
+}
+
+// splitCSV splits a comma-separated list, trimming whitespace and dropping
+// empty entries.
+func splitCSV(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part != "" {
+			out = append(out, part)
+		}
+	}
+
+	return out
+}
+
+func installExtension(ctx context.Context, c *builders.Client, name string) error {
+	if !validExtensionName.MatchString(name) {
+		return fmt.Errorf("invalid extension name %q", name)
+	}
+
+	con, err := c.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer con.Close()
+
+	if _, err := con.Query(ctx, fmt.Sprintf("INSTALL %s;", name)); err != nil {
+		return err
+	}
+	if _, err := con.Query(ctx, fmt.Sprintf("LOAD %s;", name)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// attachDatabase attaches an external catalog given an "alias:dsn" spec,
+// e.g. "pg:postgres://user@host/db" or "lake:s3://bucket/warehouse".
+func attachDatabase(ctx context.Context, c *builders.Client, spec string) error {
+	alias, dsn, ok := strings.Cut(spec, ":")
+	if !ok {
+		return fmt.Errorf("expected \"<alias>:<dsn>\", got %q", spec)
+	}
+
+	con, err := c.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer con.Close()
+
+	query := fmt.Sprintf("ATTACH '%s' AS %s;", escapeLiteral(dsn), quoteIdent(alias))
+	_, err = con.Query(ctx, query)
+	return err
+}
@@ -0,0 +1,94 @@
+//go:build cgo && ((darwin && (amd64 || arm64)) || (linux && (amd64 || arm64 || riscv64)))
+
+package adapters
+
+import "testing"
+
+func TestValidExtensionName(t *testing.T) {
+	tests := []struct {
+		name string
+		ok   bool
+	}{
+		{"httpfs", true},
+		{"postgres_scanner", true},
+		{"httpfs; DROP TABLE users", false},
+		{"httpfs'; ATTACH '/etc/passwd' AS x", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := validExtensionName.MatchString(tt.name); got != tt.ok {
+			t.Errorf("validExtensionName.MatchString(%q) = %v, want %v", tt.name, got, tt.ok)
+		}
+	}
+}
+
+func TestSplitCSV(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []string
+	}{
+		{
+			name: "empty",
+			in:   "",
+			want: nil,
+		},
+		{
+			name: "single",
+			in:   "httpfs",
+			want: []string{"httpfs"},
+		},
+		{
+			name: "trims whitespace and drops empty entries",
+			in:   "httpfs,  postgres , ,sqlite",
+			want: []string{"httpfs", "postgres", "sqlite"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := splitCSV(tt.in)
+			if len(got) != len(tt.want) {
+				t.Fatalf("splitCSV(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("splitCSV(%q)[%d] = %q, want %q", tt.in, i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestQuoteIdent(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"mydb", `"mydb"`},
+		{`weird"name`, `"weird""name"`},
+	}
+
+	for _, tt := range tests {
+		if got := quoteIdent(tt.in); got != tt.want {
+			t.Errorf("quoteIdent(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestEscapeLiteral(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"postgres://user@host/db", "postgres://user@host/db"},
+		{"it's a dsn", "it''s a dsn"},
+	}
+
+	for _, tt := range tests {
+		if got := escapeLiteral(tt.in); got != tt.want {
+			t.Errorf("escapeLiteral(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+//go:build cgo && ((darwin && (amd64 || arm64)) || (linux && (amd64 || arm64 || riscv64)))
+
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kndndrj/nvim-dbee/dbee/core"
+)
+
+// quoteIdent quotes a DuckDB identifier (database, schema, or alias name)
+// for safe interpolation into a query.
+func quoteIdent(ident string) string {
+	return `"` + strings.ReplaceAll(ident, `"`, `""`) + `"`
+}
+
+// escapeLiteral escapes a string literal for safe interpolation into a
+// query.
+func escapeLiteral(s string) string {
+	return strings.ReplaceAll(s, `'`, `''`)
+}
+
+// queryStrings runs query and collects its single string column into a
+// slice, in row order.
+func (c *duckDriver) queryStrings(ctx context.Context, query string) ([]string, error) {
+	rows, err := c.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []string
+	for rows.HasNext() {
+		row, err := rows.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		s, ok := row[0].(string)
+		if !ok {
+			continue
+		}
+		out = append(out, s)
+	}
+
+	return out, nil
+}
+
+// databaseStructure builds the schema tree for a single attached database.
+func (c *duckDriver) databaseStructure(ctx context.Context, dbName string) ([]*core.Structure, error) {
+	schemas, err := c.queryStrings(ctx, fmt.Sprintf(
+		`SELECT schema_name FROM %s.information_schema.schemata WHERE catalog_name = '%s' ORDER BY schema_name;`,
+		quoteIdent(dbName), escapeLiteral(dbName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schemas: %w", err)
+	}
+
+	var structure []*core.Structure
+	for _, schema := range schemas {
+		children, err := c.schemaStructure(ctx, dbName, schema)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build structure for schema %q: %w", schema, err)
+		}
+
+		structure = append(structure, &core.Structure{
+			Name:     schema,
+			Schema:   schema,
+			Type:     core.StructureTypeSchema,
+			Children: children,
+		})
+	}
+
+	return structure, nil
+}
+
+// schemaStructure lists a schema's tables and views, each with its columns
+// attached as children.
+func (c *duckDriver) schemaStructure(ctx context.Context, dbName, schema string) ([]*core.Structure, error) {
+	tables, err := c.queryStrings(ctx, fmt.Sprintf(
+		`SELECT table_name FROM %s.information_schema.tables WHERE table_catalog = '%s' AND table_schema = '%s' AND table_type = 'BASE TABLE' ORDER BY table_name;`,
+		quoteIdent(dbName), escapeLiteral(dbName), escapeLiteral(schema),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+
+	views, err := c.queryStrings(ctx, fmt.Sprintf(
+		`SELECT table_name FROM %s.information_schema.views WHERE table_catalog = '%s' AND table_schema = '%s' ORDER BY table_name;`,
+		quoteIdent(dbName), escapeLiteral(dbName), escapeLiteral(schema),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list views: %w", err)
+	}
+
+	columnsByTable, err := c.columnStructure(ctx, dbName, schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list columns: %w", err)
+	}
+
+	var structure []*core.Structure
+	for _, table := range tables {
+		structure = append(structure, &core.Structure{
+			Name:     table,
+			Schema:   schema,
+			Type:     core.StructureTypeTable,
+			Children: columnsByTable[table],
+		})
+	}
+	for _, view := range views {
+		structure = append(structure, &core.Structure{
+			Name:     view,
+			Schema:   schema,
+			Type:     core.StructureTypeView,
+			Children: columnsByTable[view],
+		})
+	}
+
+	return structure, nil
+}
+
+// columnStructure returns the columns of every table/view in schema, keyed
+// by table name and ordered by their position in the table.
+func (c *duckDriver) columnStructure(ctx context.Context, dbName, schema string) (map[string][]*core.Structure, error) {
+	rows, err := c.Query(ctx, fmt.Sprintf(
+		`SELECT table_name, column_name, data_type FROM %s.information_schema.columns WHERE table_catalog = '%s' AND table_schema = '%s' ORDER BY table_name, ordinal_position;`,
+		quoteIdent(dbName), escapeLiteral(dbName), escapeLiteral(schema),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	byTable := make(map[string][]*core.Structure)
+	for rows.HasNext() {
+		row, err := rows.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		table, _ := row[0].(string)
+		column, _ := row[1].(string)
+		dataType, _ := row[2].(string)
+
+		byTable[table] = append(byTable[table], &core.Structure{
+			Name:   fmt.Sprintf("%s (%s)", column, dataType),
+			Schema: schema,
+			Type:   core.StructureTypeColumn,
+		})
+	}
+
+	return byTable, nil
+}
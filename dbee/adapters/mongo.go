@@ -29,18 +29,18 @@ func init() {
 	gob.Register(bson.M{})
 	gob.Register(bson.D{})
 	gob.Register(primitive.ObjectID{})
-	// gob.Register(primitive.DateTime)
+	gob.Register(primitive.DateTime(0))
 	gob.Register(primitive.Binary{})
 	gob.Register(primitive.Regex{})
-	// gob.Register(primitive.JavaScript)
+	gob.Register(primitive.JavaScript(""))
 	gob.Register(primitive.CodeWithScope{})
 	gob.Register(primitive.Timestamp{})
 	gob.Register(primitive.Decimal128{})
-	// gob.Register(primitive.MinKey{})
-	// gob.Register(primitive.MaxKey{})
-	// gob.Register(primitive.Undefined{})
+	gob.Register(primitive.MinKey{})
+	gob.Register(primitive.MaxKey{})
+	gob.Register(primitive.Undefined{})
 	gob.Register(primitive.DBPointer{})
-	// gob.Register(primitive.Symbol)
+	gob.Register(primitive.Symbol(""))
 }
 
 var _ core.Adapter = (*Mongo)(nil)
@@ -54,6 +54,16 @@ func (m *Mongo) Connect(rawURL string) (core.Driver, error) {
 		return nil, fmt.Errorf("mongo: invalid url: %w", err)
 	}
 
+	extJSON, err := parseExtJSONMode(u.Query().Get("extjson"))
+	if err != nil {
+		return nil, fmt.Errorf("mongo: %w", err)
+	}
+
+	sampleSize, err := parseSampleSize(u.Query().Get("sampleSize"))
+	if err != nil {
+		return nil, fmt.Errorf("mongo: %w", err)
+	}
+
 	opts := options.Client().ApplyURI(rawURL)
 	client, err := mongo.Connect(context.TODO(), opts)
 	if err != nil {
@@ -61,8 +71,10 @@ func (m *Mongo) Connect(rawURL string) (core.Driver, error) {
 	}
 
 	return &mongoDriver{
-		c:      client,
-		dbName: u.Path[1:],
+		c:          client,
+		dbName:     u.Path[1:],
+		extJSON:    extJSON,
+		sampleSize: sampleSize,
 	}, nil
 }
 
@@ -72,8 +84,10 @@ var (
 )
 
 type mongoDriver struct {
-	c      *mongo.Client
-	dbName string
+	c          *mongo.Client
+	dbName     string
+	extJSON    extJSONMode
+	sampleSize int
 }
 
 func (c *mongoDriver) getCurrentDatabase(ctx context.Context) (string, error) {
@@ -100,10 +114,9 @@ func (c *mongoDriver) Query(ctx context.Context, query string) (core.ResultStrea
 	}
 	db := c.c.Database(dbName)
 
-	var command any
-	err = bson.UnmarshalExtJSON([]byte(query), false, &command)
+	command, err := parseCommand(query)
 	if err != nil {
-		return nil, fmt.Errorf("cannot marshal command: \"%v\" to bson: %v", query, err)
+		return nil, err
 	}
 
 	var resp bson.M
@@ -130,13 +143,13 @@ func (c *mongoDriver) Query(ctx context.Context, query string) (core.ResultStrea
 					continue
 				}
 				for _, item := range batch {
-					yield(newMongoResponse(item))
+					yield(newMongoResponse(item, c.extJSON))
 				}
 			}
 			return nil
 		})
 	} else {
-		next, hasNext = builders.NextSingle(newMongoResponse(resp))
+		next, hasNext = builders.NextSingle(newMongoResponse(resp, c.extJSON))
 	}
 
 	// build result
@@ -158,19 +171,40 @@ func (c *mongoDriver) Structure() ([]*core.Structure, error) {
 	if err != nil {
 		return nil, err
 	}
+	db := c.c.Database(dbName)
 
-	collections, err := c.c.Database(dbName).ListCollectionNames(ctx, bson.D{})
+	collections, err := db.ListCollectionNames(ctx, bson.D{})
 	if err != nil {
 		return nil, err
 	}
 
 	var structure []*core.Structure
+	gridfsBuckets := make(map[string]struct{})
 
 	for _, coll := range collections {
+		if bucket, ok := gridfsBucket(coll); ok {
+			gridfsBuckets[bucket] = struct{}{}
+			continue
+		}
+
+		children, err := c.collectionStructure(ctx, db, coll)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build structure for collection %q: %w", coll, err)
+		}
+
+		structure = append(structure, &core.Structure{
+			Name:     coll,
+			Schema:   "",
+			Type:     core.StructureTypeTable,
+			Children: children,
+		})
+	}
+
+	for bucket := range gridfsBuckets {
 		structure = append(structure, &core.Structure{
-			Name:   coll,
+			Name:   bucket,
 			Schema: "",
-			Type:   core.StructureTypeTable,
+			Type:   core.StructureTypeGridfs,
 		})
 	}
 
@@ -214,17 +248,27 @@ func (c *mongoDriver) SelectDatabase(name string) error {
 // mongoResponse serves as a wrapper around the mongo response
 // to stringify the return values
 type mongoResponse struct {
-	value any
+	value   any
+	extJSON extJSONMode
 }
 
-func newMongoResponse(val any) *mongoResponse {
+func newMongoResponse(val any, extJSON extJSONMode) *mongoResponse {
 	return &mongoResponse{
-		value: val,
+		value:   val,
+		extJSON: extJSON,
 	}
 }
 
 func (mr *mongoResponse) String() string {
-	parsed, err := json.MarshalIndent(mr.value, "", "  ")
+	if mr.extJSON == extJSONPlain {
+		parsed, err := json.MarshalIndent(mr.value, "", "  ")
+		if err != nil {
+			return fmt.Sprint(mr.value)
+		}
+		return string(parsed)
+	}
+
+	parsed, err := bson.MarshalExtJSONIndent(mr.value, mr.extJSON == extJSONCanonical, false, "", "  ")
 	if err != nil {
 		return fmt.Sprint(mr.value)
 	}
@@ -232,27 +276,38 @@ func (mr *mongoResponse) String() string {
 }
 
 func (mr *mongoResponse) MarshalJSON() ([]byte, error) {
-	return json.Marshal(mr.value)
+	if mr.extJSON == extJSONPlain {
+		return json.Marshal(mr.value)
+	}
+
+	return bson.MarshalExtJSON(mr.value, mr.extJSON == extJSONCanonical, false)
+}
+
+// mongoResponseGob is the on-the-wire representation of a mongoResponse for
+// gob, so the extJSON mode survives a round trip through the result cache.
+type mongoResponseGob struct {
+	Value   any
+	ExtJSON extJSONMode
 }
 
 func (mr *mongoResponse) GobEncode() ([]byte, error) {
-	var err error
 	w := new(bytes.Buffer)
 	encoder := gob.NewEncoder(w)
-	err = encoder.Encode(mr.value)
+	err := encoder.Encode(mongoResponseGob{Value: mr.value, ExtJSON: mr.extJSON})
 	if err != nil {
 		return nil, err
 	}
-	return w.Bytes(), err
+	return w.Bytes(), nil
 }
 
 func (mr *mongoResponse) GobDecode(buf []byte) error {
-	var err error
 	r := bytes.NewBuffer(buf)
 	decoder := gob.NewDecoder(r)
-	err = decoder.Decode(&mr.value)
-	if err != nil {
+	var g mongoResponseGob
+	if err := decoder.Decode(&g); err != nil {
 		return err
 	}
-	return err
+	mr.value = g.Value
+	mr.extJSON = g.ExtJSON
+	return nil
 }
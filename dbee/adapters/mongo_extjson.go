@@ -0,0 +1,35 @@
+package adapters
+
+import "fmt"
+
+// extJSONMode controls how mongo query results are marshalled to JSON.
+type extJSONMode int
+
+const (
+	// extJSONRelaxed uses MongoDB's relaxed extended JSON, which keeps BSON
+	// type info for types that don't have a native JSON representation
+	// while staying close to plain JSON for everything else. This is the
+	// default.
+	extJSONRelaxed extJSONMode = iota
+	// extJSONCanonical uses canonical extended JSON, preserving exact BSON
+	// type fidelity (e.g. distinguishing int32 from int64) so results can
+	// be pasted back into a query unchanged.
+	extJSONCanonical
+	// extJSONPlain uses plain encoding/json, same as before extended JSON
+	// support existed; BSON-specific types lose their type identity.
+	extJSONPlain
+)
+
+// parseExtJSONMode parses the "extjson" connection string param.
+func parseExtJSONMode(s string) (extJSONMode, error) {
+	switch s {
+	case "", "relaxed":
+		return extJSONRelaxed, nil
+	case "canonical":
+		return extJSONCanonical, nil
+	case "plain":
+		return extJSONPlain, nil
+	default:
+		return 0, fmt.Errorf("unknown extjson mode %q, expected canonical, relaxed or plain", s)
+	}
+}
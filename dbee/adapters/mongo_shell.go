@@ -0,0 +1,537 @@
+package adapters
+
+import (
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// parseCommand translates a query string into a runCommand document. It
+// accepts either raw extended JSON (starting with '{', the original
+// behaviour) or mongosh/mgo-shell-style syntax in the form
+// `db.<collection>.<op>(<args>).<modifier>(<args>)...`.
+func parseCommand(query string) (any, error) {
+	trimmed := strings.TrimSpace(query)
+
+	if strings.HasPrefix(trimmed, "{") {
+		var command any
+		if err := bson.UnmarshalExtJSON([]byte(trimmed), false, &command); err != nil {
+			return nil, fmt.Errorf("cannot marshal command: \"%v\" to bson: %v", query, err)
+		}
+		return command, nil
+	}
+
+	_, command, err := parseShellQuery(trimmed)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse shell query: %w", err)
+	}
+	return command, nil
+}
+
+// shellCall represents a single method call parsed out of shell-style
+// syntax, e.g. the `sort({name: 1})` in `db.users.find().sort({name: 1})`.
+type shellCall struct {
+	method string
+	args   []string
+}
+
+// parseShellQuery parses a `db.<collection>.<op>(...)` query, together with
+// any chained modifier calls, and translates it into a runCommand document.
+func parseShellQuery(query string) (collection string, cmd bson.D, err error) {
+	if !strings.HasPrefix(query, "db.") {
+		return "", nil, fmt.Errorf("expected query to start with \"db.\"")
+	}
+	rest := query[len("db."):]
+
+	dot := strings.Index(rest, ".")
+	if dot < 0 {
+		return "", nil, fmt.Errorf("missing collection method")
+	}
+	collection = rest[:dot]
+	rest = rest[dot+1:]
+
+	calls, err := parseShellCalls(rest)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(calls) == 0 {
+		return "", nil, fmt.Errorf("no method call found")
+	}
+
+	cmd, err = buildCommand(collection, calls[0], calls[1:])
+	if err != nil {
+		return "", nil, err
+	}
+
+	return collection, cmd, nil
+}
+
+// parseShellCalls splits a chain such as
+// `find({...}, {...}).sort({...}).limit(10)` into individual method calls,
+// respecting brace/bracket/paren nesting and quoted strings inside
+// arguments.
+func parseShellCalls(s string) ([]shellCall, error) {
+	var calls []shellCall
+
+	for len(s) > 0 {
+		open := strings.Index(s, "(")
+		if open < 0 {
+			return nil, fmt.Errorf("expected '(' near %q", s)
+		}
+		method := s[:open]
+		if method == "" {
+			return nil, fmt.Errorf("missing method name")
+		}
+
+		closeIdx, err := matchingParen(s, open)
+		if err != nil {
+			return nil, err
+		}
+
+		args, err := splitTopLevelArgs(s[open+1 : closeIdx])
+		if err != nil {
+			return nil, err
+		}
+		calls = append(calls, shellCall{method: method, args: args})
+
+		s = s[closeIdx+1:]
+		if s == "" {
+			break
+		}
+		if !strings.HasPrefix(s, ".") {
+			return nil, fmt.Errorf("expected '.' after %q, got %q", method, s)
+		}
+		s = s[1:]
+	}
+
+	return calls, nil
+}
+
+// matchingParen returns the index of the ')' matching the '(' at position
+// open, respecting nested braces/brackets/parens and quoted strings.
+func matchingParen(s string, open int) (int, error) {
+	depth := 0
+	var quote byte
+	for i := open; i < len(s); i++ {
+		c := s[i]
+		if quote != 0 {
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch c {
+		case '\'', '"':
+			quote = c
+		case '(', '{', '[':
+			depth++
+		case ')', '}', ']':
+			depth--
+			if depth == 0 && c == ')' {
+				return i, nil
+			}
+		}
+	}
+	return -1, fmt.Errorf("unbalanced parentheses")
+}
+
+// splitTopLevelArgs splits an argument list on commas that are not nested
+// inside braces, brackets, parens, or quoted strings.
+func splitTopLevelArgs(s string) ([]string, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+
+	var args []string
+	depth := 0
+	var quote byte
+	start := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if quote != 0 {
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch c {
+		case '\'', '"':
+			quote = c
+		case '{', '[', '(':
+			depth++
+		case '}', ']', ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				args = append(args, strings.TrimSpace(s[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 || quote != 0 {
+		return nil, fmt.Errorf("unbalanced argument list: %q", s)
+	}
+	args = append(args, strings.TrimSpace(s[start:]))
+
+	return args, nil
+}
+
+// argAt extended-JSON-decodes the i-th argument, returning nil if it was
+// omitted so optional arguments (e.g. the projection in find) can be left
+// out entirely.
+func argAt(args []string, i int) (any, error) {
+	if i >= len(args) || args[i] == "" {
+		return nil, nil
+	}
+
+	withConstructors, err := rewriteShellConstructors(args[i])
+	if err != nil {
+		return nil, fmt.Errorf("invalid argument %q: %w", args[i], err)
+	}
+
+	var v any
+	if err := bson.UnmarshalExtJSON([]byte(normalizeShellJSON(withConstructors)), false, &v); err != nil {
+		return nil, fmt.Errorf("invalid argument %q: %w", args[i], err)
+	}
+	return v, nil
+}
+
+// normalizeShellJSON rewrites a mongosh/JS-object-literal-style document
+// into strict JSON so it can be handed to bson.UnmarshalExtJSON: bare,
+// unquoted object keys are quoted (`{age:{$gt:30}}` -> `{"age":{"$gt":30}}`)
+// and single-quoted strings become double-quoted JSON strings. Already
+// double-quoted keys/strings are copied through unchanged.
+func normalizeShellJSON(s string) string {
+	var out strings.Builder
+
+	type frame struct{ isObject bool }
+	var stack []frame
+	expectKey := false
+
+	n := len(s)
+	for i := 0; i < n; {
+		c := s[i]
+
+		switch c {
+		case '\'':
+			j := i + 1
+			var val strings.Builder
+			for j < n && s[j] != '\'' {
+				if s[j] == '\\' && j+1 < n {
+					val.WriteByte(s[j])
+					j++
+				}
+				val.WriteByte(s[j])
+				j++
+			}
+			out.WriteByte('"')
+			out.WriteString(strings.ReplaceAll(val.String(), `"`, `\"`))
+			out.WriteByte('"')
+			i = j + 1
+			expectKey = false
+			continue
+
+		case '"':
+			j := i + 1
+			for j < n && s[j] != '"' {
+				if s[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			out.WriteString(s[i : j+1])
+			i = j + 1
+			expectKey = false
+			continue
+
+		case '{':
+			stack = append(stack, frame{isObject: true})
+			out.WriteByte(c)
+			i++
+			expectKey = true
+			continue
+
+		case '[':
+			stack = append(stack, frame{isObject: false})
+			out.WriteByte(c)
+			i++
+			expectKey = false
+			continue
+
+		case '}', ']':
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			out.WriteByte(c)
+			i++
+			expectKey = false
+			continue
+
+		case ',':
+			out.WriteByte(c)
+			i++
+			expectKey = len(stack) > 0 && stack[len(stack)-1].isObject
+			continue
+
+		case ':':
+			out.WriteByte(c)
+			i++
+			expectKey = false
+			continue
+		}
+
+		if expectKey && isShellIdentStart(c) {
+			j := i
+			for j < n && isShellIdentPart(s[j]) {
+				j++
+			}
+			out.WriteByte('"')
+			out.WriteString(s[i:j])
+			out.WriteByte('"')
+			i = j
+			expectKey = false
+			continue
+		}
+
+		out.WriteByte(c)
+		i++
+	}
+
+	return out.String()
+}
+
+func isShellIdentStart(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isShellIdentPart(c byte) bool {
+	return isShellIdentStart(c) || (c >= '0' && c <= '9')
+}
+
+// docEntries returns the field/value pairs of a parsed extended JSON
+// document, regardless of whether it decoded as bson.D or bson.M.
+func docEntries(v any) []bson.E {
+	switch d := v.(type) {
+	case bson.D:
+		return d
+	case bson.M:
+		entries := make([]bson.E, 0, len(d))
+		for k, val := range d {
+			entries = append(entries, bson.E{Key: k, Value: val})
+		}
+		return entries
+	default:
+		return nil
+	}
+}
+
+// hasKey reports whether entries already contains the given field name.
+func hasKey(entries []bson.E, key string) bool {
+	for _, e := range entries {
+		if e.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// buildCommand maps a primary method call, plus any chained modifiers
+// (.sort(), .limit(), .skip(), .projection()), onto the corresponding
+// runCommand document.
+func buildCommand(collection string, primary shellCall, chain []shellCall) (bson.D, error) {
+	var cmd bson.D
+
+	switch primary.method {
+	case "find", "findOne":
+		filter, err := argAt(primary.args, 0)
+		if err != nil {
+			return nil, err
+		}
+		if filter == nil {
+			filter = bson.M{}
+		}
+		cmd = bson.D{{Key: "find", Value: collection}, {Key: "filter", Value: filter}}
+
+		projection, err := argAt(primary.args, 1)
+		if err != nil {
+			return nil, err
+		}
+		if projection != nil {
+			cmd = append(cmd, bson.E{Key: "projection", Value: projection})
+		}
+
+		if primary.method == "findOne" {
+			cmd = append(cmd, bson.E{Key: "limit", Value: int64(1)})
+		}
+
+	case "aggregate":
+		pipeline, err := argAt(primary.args, 0)
+		if err != nil {
+			return nil, err
+		}
+		if pipeline == nil {
+			pipeline = bson.A{}
+		}
+		cmd = bson.D{
+			{Key: "aggregate", Value: collection},
+			{Key: "pipeline", Value: pipeline},
+			{Key: "cursor", Value: bson.M{}},
+		}
+
+	case "count":
+		query, err := argAt(primary.args, 0)
+		if err != nil {
+			return nil, err
+		}
+		if query == nil {
+			query = bson.M{}
+		}
+		cmd = bson.D{{Key: "count", Value: collection}, {Key: "query", Value: query}}
+
+	case "distinct":
+		field, err := argAt(primary.args, 0)
+		if err != nil {
+			return nil, err
+		}
+		query, err := argAt(primary.args, 1)
+		if err != nil {
+			return nil, err
+		}
+		if query == nil {
+			query = bson.M{}
+		}
+		cmd = bson.D{
+			{Key: "distinct", Value: collection},
+			{Key: "key", Value: field},
+			{Key: "query", Value: query},
+		}
+
+	case "insertOne":
+		doc, err := argAt(primary.args, 0)
+		if err != nil {
+			return nil, err
+		}
+		cmd = bson.D{
+			{Key: "insert", Value: collection},
+			{Key: "documents", Value: bson.A{doc}},
+		}
+
+	case "insertMany":
+		docs, err := argAt(primary.args, 0)
+		if err != nil {
+			return nil, err
+		}
+		cmd = bson.D{
+			{Key: "insert", Value: collection},
+			{Key: "documents", Value: docs},
+		}
+
+	case "updateOne", "updateMany":
+		filter, err := argAt(primary.args, 0)
+		if err != nil {
+			return nil, err
+		}
+		update, err := argAt(primary.args, 1)
+		if err != nil {
+			return nil, err
+		}
+		cmd = bson.D{
+			{Key: "update", Value: collection},
+			{Key: "updates", Value: bson.A{
+				bson.M{"q": filter, "u": update, "multi": primary.method == "updateMany"},
+			}},
+		}
+
+	case "deleteOne", "deleteMany":
+		filter, err := argAt(primary.args, 0)
+		if err != nil {
+			return nil, err
+		}
+		limit := 1
+		if primary.method == "deleteMany" {
+			limit = 0
+		}
+		cmd = bson.D{
+			{Key: "delete", Value: collection},
+			{Key: "deletes", Value: bson.A{
+				bson.M{"q": filter, "limit": limit},
+			}},
+		}
+
+	case "createIndex":
+		keys, err := argAt(primary.args, 0)
+		if err != nil {
+			return nil, err
+		}
+		opts, err := argAt(primary.args, 1)
+		if err != nil {
+			return nil, err
+		}
+		optEntries := docEntries(opts)
+		index := bson.D{{Key: "key", Value: keys}}
+		if !hasKey(optEntries, "name") {
+			index = append(index, bson.E{Key: "name", Value: indexName(keys)})
+		}
+		index = append(index, optEntries...)
+		cmd = bson.D{
+			{Key: "createIndexes", Value: collection},
+			{Key: "indexes", Value: bson.A{index}},
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported method %q", primary.method)
+	}
+
+	for _, c := range chain {
+		if err := applyModifier(&cmd, c); err != nil {
+			return nil, err
+		}
+	}
+
+	return cmd, nil
+}
+
+// applyModifier folds a chained call like .sort(), .limit(), .skip() or
+// .projection() into the command document being built.
+func applyModifier(cmd *bson.D, call shellCall) error {
+	var key string
+	switch call.method {
+	case "sort":
+		key = "sort"
+	case "limit":
+		key = "limit"
+	case "skip":
+		key = "skip"
+	case "projection", "project":
+		key = "projection"
+	default:
+		return fmt.Errorf("unsupported chained method %q", call.method)
+	}
+
+	v, err := argAt(call.args, 0)
+	if err != nil {
+		return err
+	}
+	*cmd = append(*cmd, bson.E{Key: key, Value: v})
+
+	return nil
+}
+
+// indexName derives the default index name mongo itself generates for a key
+// document, e.g. {name: 1} -> "name_1".
+func indexName(keys any) string {
+	var parts []string
+	for _, e := range docEntries(keys) {
+		parts = append(parts, fmt.Sprintf("%s_%v", e.Key, e.Value))
+	}
+	if len(parts) == 0 {
+		return "index"
+	}
+	return strings.Join(parts, "_")
+}
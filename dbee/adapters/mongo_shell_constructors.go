@@ -0,0 +1,160 @@
+package adapters
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// shellConstructors maps mongosh constructor names (after an optional
+// leading "new ") to the $-prefixed extended JSON field they translate to,
+// e.g. `ObjectId("507f...")` -> `{"$oid":"507f..."}`.
+var shellConstructors = map[string]string{
+	"ObjectId":      "$oid",
+	"ISODate":       "$date",
+	"Date":          "$date",
+	"NumberLong":    "$numberLong",
+	"NumberInt":     "$numberInt",
+	"NumberDecimal": "$numberDecimal",
+}
+
+// rewriteShellConstructors replaces mongosh constructor calls - the most
+// common tokens in copy-pasted shell queries, e.g. `ObjectId("...")`,
+// `ISODate("...")`, `new Date()`, `NumberLong(...)` - with their extended
+// JSON equivalents, so the result can be fed through normalizeShellJSON and
+// bson.UnmarshalExtJSON.
+func rewriteShellConstructors(s string) (string, error) {
+	var out strings.Builder
+	n := len(s)
+	var quote byte
+
+	for i := 0; i < n; {
+		c := s[i]
+
+		if quote != 0 {
+			out.WriteByte(c)
+			if c == '\\' && i+1 < n {
+				i++
+				out.WriteByte(s[i])
+			} else if c == quote {
+				quote = 0
+			}
+			i++
+			continue
+		}
+
+		if c == '\'' || c == '"' {
+			quote = c
+			out.WriteByte(c)
+			i++
+			continue
+		}
+
+		if i == 0 || !isShellIdentPart(s[i-1]) {
+			if field, open, ok := matchShellConstructor(s, i); ok {
+				closeIdx, err := matchingParen(s, open)
+				if err != nil {
+					return "", err
+				}
+
+				literal, err := shellConstructorLiteral(field, s[open+1:closeIdx])
+				if err != nil {
+					return "", err
+				}
+
+				out.WriteString(literal)
+				i = closeIdx + 1
+				continue
+			}
+		}
+
+		out.WriteByte(c)
+		i++
+	}
+
+	return out.String(), nil
+}
+
+// matchShellConstructor checks whether a known constructor call (optionally
+// preceded by "new ") starts at position i, returning the extended JSON
+// field it maps to and the index of the call's opening '('.
+func matchShellConstructor(s string, i int) (field string, open int, ok bool) {
+	n := len(s)
+	j := i
+
+	if hasShellKeyword(s, j, "new") {
+		j += len("new")
+		for j < n && (s[j] == ' ' || s[j] == '\t') {
+			j++
+		}
+	}
+
+	start := j
+	for j < n && isShellIdentPart(s[j]) {
+		j++
+	}
+	if j == start {
+		return "", 0, false
+	}
+
+	field, known := shellConstructors[s[start:j]]
+	if !known {
+		return "", 0, false
+	}
+
+	for j < n && (s[j] == ' ' || s[j] == '\t') {
+		j++
+	}
+	if j >= n || s[j] != '(' {
+		return "", 0, false
+	}
+
+	return field, j, true
+}
+
+// hasShellKeyword reports whether s[i:] starts with word as a whole
+// identifier, not as a prefix of a longer one.
+func hasShellKeyword(s string, i int, word string) bool {
+	if !strings.HasPrefix(s[i:], word) {
+		return false
+	}
+	end := i + len(word)
+	return end >= len(s) || !isShellIdentPart(s[end])
+}
+
+// shellConstructorLiteral builds the extended JSON literal for a
+// constructor call, given its mapped field and raw (un-normalized)
+// argument, recursively rewriting any constructors nested in the argument.
+// A missing argument defaults to "now" for $date and is otherwise an error.
+func shellConstructorLiteral(field, rawArg string) (string, error) {
+	arg := strings.TrimSpace(rawArg)
+
+	rewritten, err := rewriteShellConstructors(arg)
+	if err != nil {
+		return "", err
+	}
+
+	if rewritten == "" {
+		if field != "$date" {
+			return "", fmt.Errorf("constructor for %q requires an argument", field)
+		}
+		rewritten = fmt.Sprintf("%q", time.Now().UTC().Format("2006-01-02T15:04:05.000Z"))
+	}
+
+	return fmt.Sprintf(`{%q:%s}`, field, asExtJSONStringValue(rewritten)), nil
+}
+
+// asExtJSONStringValue ensures a constructor argument is a JSON string
+// literal, quoting bare values (e.g. the 123 in NumberLong(123)) and
+// leaving already-quoted strings, objects, or arrays untouched.
+func asExtJSONStringValue(s string) string {
+	if s == "" {
+		return `""`
+	}
+	switch s[0] {
+	case '"', '\'', '{', '[':
+		return s
+	default:
+		return `"` + s + `"`
+	}
+}
@@ -0,0 +1,277 @@
+package adapters
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestNormalizeShellJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "bare keys and operators",
+			in:   "{age:{$gt:30}}",
+			want: `{"age":{"$gt":30}}`,
+		},
+		{
+			name: "single-quoted string value",
+			in:   "{name:'bob'}",
+			want: `{"name":"bob"}`,
+		},
+		{
+			name: "already double-quoted keys and strings pass through",
+			in:   `{"name": "bob"}`,
+			want: `{"name": "bob"}`,
+		},
+		{
+			name: "bare keys inside an array of objects",
+			in:   "[{a:1},{b:2}]",
+			want: `[{"a":1},{"b":2}]`,
+		},
+		{
+			name: "request's canonical find example",
+			in:   "{age:{$gt:30}}, {name:1}",
+			want: `{"age":{"$gt":30}}, {"name":1}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := normalizeShellJSON(tt.in)
+			if got != tt.want {
+				t.Errorf("normalizeShellJSON(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitTopLevelArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "two documents",
+			in:   `{age:{$gt:30}}, {name:1}`,
+			want: []string{"{age:{$gt:30}}", "{name:1}"},
+		},
+		{
+			name: "comma inside a quoted string is not a split point",
+			in:   `{name:"a,b"}`,
+			want: []string{`{name:"a,b"}`},
+		},
+		{
+			name: "empty argument list",
+			in:   "",
+			want: nil,
+		},
+		{
+			name:    "unbalanced braces",
+			in:      `{name:1`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := splitTopLevelArgs(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("splitTopLevelArgs(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("splitTopLevelArgs(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("splitTopLevelArgs(%q)[%d] = %q, want %q", tt.in, i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseShellCalls(t *testing.T) {
+	calls, err := parseShellCalls(`find({age:{$gt:30}}, {name:1}).sort({name:1}).limit(10)`)
+	if err != nil {
+		t.Fatalf("parseShellCalls: %v", err)
+	}
+	if len(calls) != 3 {
+		t.Fatalf("got %d calls, want 3: %+v", len(calls), calls)
+	}
+	if calls[0].method != "find" || len(calls[0].args) != 2 {
+		t.Errorf("calls[0] = %+v", calls[0])
+	}
+	if calls[1].method != "sort" || len(calls[1].args) != 1 {
+		t.Errorf("calls[1] = %+v", calls[1])
+	}
+	if calls[2].method != "limit" || calls[2].args[0] != "10" {
+		t.Errorf("calls[2] = %+v", calls[2])
+	}
+
+	if _, err := parseShellCalls(`find({`); err == nil {
+		t.Error("expected error on unbalanced input")
+	}
+}
+
+func TestParseShellQuery(t *testing.T) {
+	coll, cmd, err := parseShellQuery(`db.users.find({age:{$gt:30}}, {name:1}).sort({name:1}).limit(10)`)
+	if err != nil {
+		t.Fatalf("parseShellQuery: %v", err)
+	}
+	if coll != "users" {
+		t.Errorf("collection = %q, want %q", coll, "users")
+	}
+
+	m := cmd.Map()
+	if m["find"] != "users" {
+		t.Errorf("find = %v, want %q", m["find"], "users")
+	}
+	if _, ok := m["sort"]; !ok {
+		t.Error("expected sort to be set from the chained modifier")
+	}
+	if _, ok := m["limit"]; !ok {
+		t.Error("expected limit to be set from the chained modifier")
+	}
+}
+
+func TestParseCommandFallsBackToExtJSON(t *testing.T) {
+	cmd, err := parseCommand(`{"ping": 1}`)
+	if err != nil {
+		t.Fatalf("parseCommand: %v", err)
+	}
+	m, ok := cmd.(bson.D)
+	if !ok {
+		t.Fatalf("expected bson.D, got %T", cmd)
+	}
+	if m.Map()["ping"] != int32(1) {
+		t.Errorf("ping = %v, want 1", m.Map()["ping"])
+	}
+}
+
+func TestBuildCommandCreateIndexRespectsExplicitName(t *testing.T) {
+	cmd, err := buildCommand("users", shellCall{
+		method: "createIndex",
+		args:   []string{"{email:1}", `{name:"custom_idx"}`},
+	}, nil)
+	if err != nil {
+		t.Fatalf("buildCommand: %v", err)
+	}
+
+	indexes, ok := cmd.Map()["indexes"].(bson.A)
+	if !ok || len(indexes) != 1 {
+		t.Fatalf("unexpected indexes value: %#v", cmd.Map()["indexes"])
+	}
+
+	index, ok := indexes[0].(bson.D)
+	if !ok {
+		t.Fatalf("unexpected index type: %T", indexes[0])
+	}
+
+	var names []string
+	for _, e := range index {
+		if e.Key == "name" {
+			names = append(names, e.Value.(string))
+		}
+	}
+	if len(names) != 1 {
+		t.Fatalf("expected exactly one \"name\" entry, got %v", names)
+	}
+	if names[0] != "custom_idx" {
+		t.Errorf("name = %q, want %q", names[0], "custom_idx")
+	}
+}
+
+func TestBuildCommandCreateIndexGeneratesDefaultName(t *testing.T) {
+	cmd, err := buildCommand("users", shellCall{
+		method: "createIndex",
+		args:   []string{"{email:1}"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("buildCommand: %v", err)
+	}
+
+	indexes := cmd.Map()["indexes"].(bson.A)
+	index := indexes[0].(bson.D)
+	if index.Map()["name"] != "email_1" {
+		t.Errorf("name = %v, want %q", index.Map()["name"], "email_1")
+	}
+}
+
+func TestRewriteShellConstructors(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "ObjectId with double quotes",
+			in:   `{_id: ObjectId("507f1f77bcf86cd799439011")}`,
+			want: `{_id: {"$oid":"507f1f77bcf86cd799439011"}}`,
+		},
+		{
+			name: "ObjectId with single quotes",
+			in:   `{_id: ObjectId('507f1f77bcf86cd799439011')}`,
+			want: `{_id: {"$oid":'507f1f77bcf86cd799439011'}}`,
+		},
+		{
+			name: "ISODate nested inside an operator",
+			in:   `{createdAt: {$gt: ISODate("2020-01-01T00:00:00Z")}}`,
+			want: `{createdAt: {$gt: {"$date":"2020-01-01T00:00:00Z"}}}`,
+		},
+		{
+			name: "NumberLong with a bare number",
+			in:   `{big: NumberLong(123)}`,
+			want: `{big: {"$numberLong":"123"}}`,
+		},
+		{
+			name: "new Date with an argument",
+			in:   `{when: new Date("2020-01-01")}`,
+			want: `{when: {"$date":"2020-01-01"}}`,
+		},
+		{
+			name: "identifier that merely contains a constructor name is untouched",
+			in:   `{myObjectIdField: 1}`,
+			want: `{myObjectIdField: 1}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rewriteShellConstructors(tt.in)
+			if err != nil {
+				t.Fatalf("rewriteShellConstructors(%q): %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Errorf("rewriteShellConstructors(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestArgAtObjectIdExample(t *testing.T) {
+	v, err := argAt([]string{`{_id: ObjectId("507f1f77bcf86cd799439011")}`}, 0)
+	if err != nil {
+		t.Fatalf("argAt: %v", err)
+	}
+	doc, ok := v.(bson.D)
+	if !ok {
+		t.Fatalf("expected bson.D, got %T", v)
+	}
+	oid, ok := doc.Map()["_id"].(primitive.ObjectID)
+	if !ok {
+		t.Fatalf("expected primitive.ObjectID, got %T", doc.Map()["_id"])
+	}
+	if oid.Hex() != "507f1f77bcf86cd799439011" {
+		t.Errorf("oid = %s, want %s", oid.Hex(), "507f1f77bcf86cd799439011")
+	}
+}
@@ -0,0 +1,131 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/kndndrj/nvim-dbee/dbee/core"
+)
+
+// defaultSampleFieldsSize is the number of documents sampled to infer a
+// collection's top-level fields and their BSON types, used unless the
+// connection url overrides it with a "sampleSize" param.
+const defaultSampleFieldsSize = 100
+
+// parseSampleSize parses the "sampleSize" connection string param.
+func parseSampleSize(s string) (int, error) {
+	if s == "" {
+		return defaultSampleFieldsSize, nil
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid sampleSize %q, expected a positive integer", s)
+	}
+
+	return n, nil
+}
+
+// gridfsBucket reports whether coll is one of the two collections
+// ("<bucket>.files" / "<bucket>.chunks") that make up a GridFS bucket, and
+// if so returns the bucket name.
+func gridfsBucket(coll string) (string, bool) {
+	if bucket, ok := strings.CutSuffix(coll, ".files"); ok {
+		return bucket, true
+	}
+	if bucket, ok := strings.CutSuffix(coll, ".chunks"); ok {
+		return bucket, true
+	}
+	return "", false
+}
+
+// collectionStructure builds the child nodes (indexes, then inferred
+// fields) for a single collection.
+func (c *mongoDriver) collectionStructure(ctx context.Context, db *mongo.Database, coll string) ([]*core.Structure, error) {
+	var children []*core.Structure
+
+	indexes, err := c.indexStructure(ctx, db, coll)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list indexes: %w", err)
+	}
+	children = append(children, indexes...)
+
+	fields, err := c.fieldStructure(ctx, db, coll)
+	if err != nil {
+		return nil, fmt.Errorf("failed to infer fields: %w", err)
+	}
+	children = append(children, fields...)
+
+	return children, nil
+}
+
+func (c *mongoDriver) indexStructure(ctx context.Context, db *mongo.Database, coll string) ([]*core.Structure, error) {
+	cur, err := db.Collection(coll).Indexes().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var indexes []bson.M
+	if err := cur.All(ctx, &indexes); err != nil {
+		return nil, err
+	}
+
+	var structure []*core.Structure
+	for _, idx := range indexes {
+		name, _ := idx["name"].(string)
+		structure = append(structure, &core.Structure{
+			Name:   name,
+			Schema: "",
+			Type:   core.StructureTypeIndex,
+		})
+	}
+
+	return structure, nil
+}
+
+// sampledField is the aggregation result of grouping a sample of documents
+// by their top-level field names.
+type sampledField struct {
+	Name  string   `bson:"_id"`
+	Types []string `bson:"types"`
+}
+
+// fieldStructure infers a collection's top-level fields, and their BSON
+// type(s), by sampling c.sampleSize random documents.
+func (c *mongoDriver) fieldStructure(ctx context.Context, db *mongo.Database, coll string) ([]*core.Structure, error) {
+	pipeline := bson.A{
+		bson.M{"$sample": bson.M{"size": c.sampleSize}},
+		bson.M{"$project": bson.M{"fields": bson.M{"$objectToArray": "$$ROOT"}}},
+		bson.M{"$unwind": "$fields"},
+		bson.M{"$group": bson.M{
+			"_id":   "$fields.k",
+			"types": bson.M{"$addToSet": bson.M{"$type": "$fields.v"}},
+		}},
+	}
+
+	cur, err := db.Collection(coll).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields []sampledField
+	if err := cur.All(ctx, &fields); err != nil {
+		return nil, err
+	}
+
+	var structure []*core.Structure
+	for _, f := range fields {
+		structure = append(structure, &core.Structure{
+			Name:   fmt.Sprintf("%s (%s)", f.Name, strings.Join(f.Types, "|")),
+			Schema: "",
+			Type:   core.StructureTypeColumn,
+		})
+	}
+
+	return structure, nil
+}
@@ -0,0 +1,71 @@
+package adapters
+
+import "testing"
+
+func TestParseSampleSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "empty falls back to the default",
+			in:   "",
+			want: defaultSampleFieldsSize,
+		},
+		{
+			name: "overridden by a positive integer",
+			in:   "500",
+			want: 500,
+		},
+		{
+			name:    "zero is rejected",
+			in:      "0",
+			wantErr: true,
+		},
+		{
+			name:    "negative is rejected",
+			in:      "-10",
+			wantErr: true,
+		},
+		{
+			name:    "non-numeric is rejected",
+			in:      "lots",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSampleSize(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSampleSize(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("parseSampleSize(%q) = %d, want %d", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGridfsBucket(t *testing.T) {
+	tests := []struct {
+		coll       string
+		wantBucket string
+		wantOk     bool
+	}{
+		{"fs.files", "fs", true},
+		{"fs.chunks", "fs", true},
+		{"images.files", "images", true},
+		{"users", "", false},
+		{"files", "", false},
+	}
+
+	for _, tt := range tests {
+		bucket, ok := gridfsBucket(tt.coll)
+		if ok != tt.wantOk || bucket != tt.wantBucket {
+			t.Errorf("gridfsBucket(%q) = (%q, %v), want (%q, %v)", tt.coll, bucket, ok, tt.wantBucket, tt.wantOk)
+		}
+	}
+}
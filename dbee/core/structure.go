@@ -0,0 +1,30 @@
+package core
+
+// StructureType represents the kind of a node in a connection's drill-down
+// structure tree.
+type StructureType int
+
+const (
+	StructureTypeNone StructureType = iota
+	StructureTypeTable
+	StructureTypeColumn
+	StructureTypeIndex
+	StructureTypeGridfs
+	StructureTypeDatabase
+	StructureTypeSchema
+	StructureTypeView
+)
+
+// Structure is a single node in a connection's drill-down structure tree,
+// e.g. a table and its columns. Children, if any, are rendered as nested
+// nodes beneath it.
+type Structure struct {
+	// Name is the display name of the node.
+	Name string
+	// Schema is the schema the node belongs to, if any.
+	Schema string
+	// Type is the kind of node this is.
+	Type StructureType
+	// Children are any nested nodes, e.g. a table's columns and indexes.
+	Children []*Structure
+}